@@ -0,0 +1,90 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type BridgeCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	bridgePortCount  *prometheus.Desc
+	bridgeStpEnabled *prometheus.Desc
+}
+
+func NewBridgeCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *BridgeCollector {
+	return &BridgeCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		bridgePortCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "bridge", "port_count"),
+			"Number of ports attached to the bridge",
+			[]string{"bridge"},
+			nil,
+		),
+		bridgeStpEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "bridge", "stp_enabled"),
+			"Whether STP is enabled on the bridge",
+			[]string{"bridge"},
+			nil,
+		),
+	}
+}
+
+func (c *BridgeCollector) Name() string {
+	return "bridge"
+}
+
+func (c *BridgeCollector) Scrape(ch chan<- prometheus.Metric) error {
+	bridges := &[]ovsmodel.Bridge{}
+	err := c.ovs.List(context.TODO(), bridges)
+	if err != nil {
+		return fmt.Errorf("listing bridges: %w", err)
+	}
+
+	for _, bridge := range *bridges {
+		if !c.cfg.bridgeAllowed(bridge.Name) {
+			continue
+		}
+
+		c.collectPortCount(ch, bridge)
+		c.collectStpEnabled(ch, bridge)
+	}
+
+	return nil
+}
+
+func (c *BridgeCollector) collectPortCount(ch chan<- prometheus.Metric, bridge ovsmodel.Bridge) {
+	ch <- prometheus.MustNewConstMetric(
+		c.bridgePortCount,
+		prometheus.GaugeValue,
+		float64(len(bridge.Ports)),
+		bridge.Name,
+	)
+}
+
+func (c *BridgeCollector) collectStpEnabled(ch chan<- prometheus.Metric, bridge ovsmodel.Bridge) {
+	stpEnabled := float64(0)
+	if bridge.STPEnable {
+		stpEnabled = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.bridgeStpEnabled,
+		prometheus.GaugeValue,
+		stpEnabled,
+		bridge.Name,
+	)
+}
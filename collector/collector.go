@@ -0,0 +1,80 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("ovs", "scrape", "collector_duration_seconds"),
+		"ovs_exporter: Duration of a collector scrape.",
+		[]string{"collector"},
+		nil,
+	)
+	scrapeSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("ovs", "scrape", "collector_success"),
+		"ovs_exporter: Whether a collector scrape succeeded.",
+		[]string{"collector"},
+		nil,
+	)
+)
+
+// Scraper is implemented by collectors whose OVSDB lookups can fail
+// independently of one another, so that one bad table does not take down
+// the rest of a scrape.
+type Scraper interface {
+	Name() string
+	Scrape(ch chan<- prometheus.Metric) error
+}
+
+// Exporter wraps a set of scrapers into a single prometheus.Collector,
+// emitting per-collector duration and success metrics alongside whatever
+// each scraper collects. Modeled on the "device collector" pattern used by
+// exporters that talk to many independent subsystems from one process.
+type Exporter struct {
+	logger   log.Logger
+	scrapers []Scraper
+}
+
+// NewExporter returns a prometheus.Collector that runs each scraper in turn
+// and reports its outcome via ovs_scrape_collector_duration_seconds and
+// ovs_scrape_collector_success.
+func NewExporter(logger log.Logger, scrapers ...Scraper) prometheus.Collector {
+	return &Exporter{
+		logger:   logger,
+		scrapers: scrapers,
+	}
+}
+
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- scrapeDurationDesc
+	ch <- scrapeSuccessDesc
+}
+
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range e.scrapers {
+		e.scrapeOne(ch, s)
+	}
+}
+
+func (e *Exporter) scrapeOne(ch chan<- prometheus.Metric, s Scraper) {
+	start := time.Now()
+	err := s.Scrape(ch)
+	duration := time.Since(start).Seconds()
+
+	success := float64(1)
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Error scraping collector", "collector", s.Name(), "err", err)
+		success = 0
+	}
+
+	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, duration, s.Name())
+	ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, success, s.Name())
+}
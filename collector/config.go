@@ -0,0 +1,80 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import "regexp"
+
+// CollectorConfig controls which OVSDB rows a collector emits metrics for.
+// Filtering happens before metric emission so that expensive per-row work
+// (e.g. BFD status or statistics lookups) is skipped entirely for rows the
+// operator has excluded, which matters on chassis with thousands of ports.
+type CollectorConfig struct {
+	InterfaceNameInclude     *regexp.Regexp
+	InterfaceNameExclude     *regexp.Regexp
+	InterfaceTypeInclude     *regexp.Regexp
+	InterfaceTypeExclude     *regexp.Regexp
+	BridgeNameInclude        *regexp.Regexp
+	BridgeNameExclude        *regexp.Regexp
+	PortNameInclude          *regexp.Regexp
+	PortNameExclude          *regexp.Regexp
+	ControllerTargetInclude  *regexp.Regexp
+	ControllerTargetExclude  *regexp.Regexp
+	ManagerTargetInclude     *regexp.Regexp
+	ManagerTargetExclude     *regexp.Regexp
+	QoSTypeInclude           *regexp.Regexp
+	QoSTypeExclude           *regexp.Regexp
+	LogicalSwitchNameInclude *regexp.Regexp
+	LogicalSwitchNameExclude *regexp.Regexp
+	LogicalRouterNameInclude *regexp.Regexp
+	LogicalRouterNameExclude *regexp.Regexp
+	ChassisNameInclude       *regexp.Regexp
+	ChassisNameExclude       *regexp.Regexp
+}
+
+func (c CollectorConfig) interfaceAllowed(name, ifaceType string) bool {
+	return matches(name, c.InterfaceNameInclude, c.InterfaceNameExclude) &&
+		matches(ifaceType, c.InterfaceTypeInclude, c.InterfaceTypeExclude)
+}
+
+func (c CollectorConfig) bridgeAllowed(name string) bool {
+	return matches(name, c.BridgeNameInclude, c.BridgeNameExclude)
+}
+
+func (c CollectorConfig) portAllowed(name string) bool {
+	return matches(name, c.PortNameInclude, c.PortNameExclude)
+}
+
+func (c CollectorConfig) controllerAllowed(target string) bool {
+	return matches(target, c.ControllerTargetInclude, c.ControllerTargetExclude)
+}
+
+func (c CollectorConfig) managerAllowed(target string) bool {
+	return matches(target, c.ManagerTargetInclude, c.ManagerTargetExclude)
+}
+
+func (c CollectorConfig) qosAllowed(qosType string) bool {
+	return matches(qosType, c.QoSTypeInclude, c.QoSTypeExclude)
+}
+
+func (c CollectorConfig) logicalSwitchAllowed(name string) bool {
+	return matches(name, c.LogicalSwitchNameInclude, c.LogicalSwitchNameExclude)
+}
+
+func (c CollectorConfig) logicalRouterAllowed(name string) bool {
+	return matches(name, c.LogicalRouterNameInclude, c.LogicalRouterNameExclude)
+}
+
+func (c CollectorConfig) chassisAllowed(name string) bool {
+	return matches(name, c.ChassisNameInclude, c.ChassisNameExclude)
+}
+
+func matches(s string, include, exclude *regexp.Regexp) bool {
+	if include != nil && !include.MatchString(s) {
+		return false
+	}
+	if exclude != nil && exclude.MatchString(s) {
+		return false
+	}
+	return true
+}
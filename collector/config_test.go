@@ -0,0 +1,134 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		include string
+		exclude string
+		want    bool
+	}{
+		{name: "no filters allows everything", s: "eth0", want: true},
+		{name: "include match passes", s: "eth0", include: "^eth", want: true},
+		{name: "include mismatch blocks", s: "br-int", include: "^eth", want: false},
+		{name: "exclude match blocks", s: "eth0", exclude: "^eth", want: false},
+		{name: "exclude mismatch passes", s: "br-int", exclude: "^eth", want: true},
+		{name: "exclude wins over include when both match", s: "eth0", include: "^eth", exclude: "0$", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var include, exclude *regexp.Regexp
+			if tt.include != "" {
+				include = regexp.MustCompile(tt.include)
+			}
+			if tt.exclude != "" {
+				exclude = regexp.MustCompile(tt.exclude)
+			}
+
+			if got := matches(tt.s, include, exclude); got != tt.want {
+				t.Errorf("matches(%q, %q, %q) = %v, want %v", tt.s, tt.include, tt.exclude, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectorConfigInterfaceAllowed(t *testing.T) {
+	cfg := CollectorConfig{
+		InterfaceNameExclude: regexp.MustCompile(`^veth`),
+		InterfaceTypeInclude: regexp.MustCompile(`^(system|internal)$`),
+	}
+
+	tests := []struct {
+		name      string
+		ifaceName string
+		ifaceType string
+		want      bool
+	}{
+		{name: "allowed system interface", ifaceName: "eth0", ifaceType: "system", want: true},
+		{name: "excluded by name", ifaceName: "veth1234", ifaceType: "system", want: false},
+		{name: "excluded by type", ifaceName: "eth0", ifaceType: "patch", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.interfaceAllowed(tt.ifaceName, tt.ifaceType); got != tt.want {
+				t.Errorf("interfaceAllowed(%q, %q) = %v, want %v", tt.ifaceName, tt.ifaceType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectorConfigPerCollectorFilters(t *testing.T) {
+	cfg := CollectorConfig{
+		BridgeNameInclude:        regexp.MustCompile(`^br-`),
+		PortNameExclude:          regexp.MustCompile(`^patch-`),
+		ControllerTargetInclude:  regexp.MustCompile(`^tcp:`),
+		ManagerTargetExclude:     regexp.MustCompile(`^ptcp:`),
+		QoSTypeInclude:           regexp.MustCompile(`^linux-htb$`),
+		LogicalSwitchNameExclude: regexp.MustCompile(`^join$`),
+		LogicalRouterNameInclude: regexp.MustCompile(`^lr-`),
+		ChassisNameExclude:       regexp.MustCompile(`^draining-`),
+	}
+
+	if !cfg.bridgeAllowed("br-int") {
+		t.Error("bridgeAllowed(br-int) = false, want true")
+	}
+	if cfg.bridgeAllowed("int") {
+		t.Error("bridgeAllowed(int) = true, want false")
+	}
+
+	if cfg.portAllowed("patch-int-to-ex") {
+		t.Error("portAllowed(patch-int-to-ex) = true, want false")
+	}
+	if !cfg.portAllowed("eth0") {
+		t.Error("portAllowed(eth0) = false, want true")
+	}
+
+	if !cfg.controllerAllowed("tcp:127.0.0.1:6633") {
+		t.Error("controllerAllowed(tcp:...) = false, want true")
+	}
+	if cfg.controllerAllowed("ssl:127.0.0.1:6633") {
+		t.Error("controllerAllowed(ssl:...) = true, want false")
+	}
+
+	if cfg.managerAllowed("ptcp:6640") {
+		t.Error("managerAllowed(ptcp:6640) = true, want false")
+	}
+
+	if !cfg.qosAllowed("linux-htb") {
+		t.Error("qosAllowed(linux-htb) = false, want true")
+	}
+	if cfg.qosAllowed("linux-hfsc") {
+		t.Error("qosAllowed(linux-hfsc) = true, want false")
+	}
+
+	if cfg.logicalSwitchAllowed("join") {
+		t.Error("logicalSwitchAllowed(join) = true, want false")
+	}
+	if !cfg.logicalSwitchAllowed("ls1") {
+		t.Error("logicalSwitchAllowed(ls1) = false, want true")
+	}
+
+	if !cfg.logicalRouterAllowed("lr-external") {
+		t.Error("logicalRouterAllowed(lr-external) = false, want true")
+	}
+	if cfg.logicalRouterAllowed("cluster-router") {
+		t.Error("logicalRouterAllowed(cluster-router) = true, want false")
+	}
+
+	if cfg.chassisAllowed("draining-chassis-1") {
+		t.Error("chassisAllowed(draining-chassis-1) = true, want false")
+	}
+	if !cfg.chassisAllowed("chassis-1") {
+		t.Error("chassisAllowed(chassis-1) = false, want true")
+	}
+}
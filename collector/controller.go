@@ -0,0 +1,101 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type ControllerCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	controllerConnected *prometheus.Desc
+	controllerRole      *prometheus.Desc
+}
+
+func NewControllerCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *ControllerCollector {
+	return &ControllerCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		controllerConnected: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "controller", "connected"),
+			"Whether the switch has an active connection to the controller",
+			[]string{"target"},
+			nil,
+		),
+		controllerRole: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "controller", "role"),
+			"Controller role (-1 = other, 0 = slave, 1 = master)",
+			[]string{"target"},
+			nil,
+		),
+	}
+}
+
+func (c *ControllerCollector) Name() string {
+	return "controller"
+}
+
+func (c *ControllerCollector) Scrape(ch chan<- prometheus.Metric) error {
+	controllers := &[]ovsmodel.Controller{}
+	err := c.ovs.List(context.TODO(), controllers)
+	if err != nil {
+		return fmt.Errorf("listing controllers: %w", err)
+	}
+
+	for _, controller := range *controllers {
+		if !c.cfg.controllerAllowed(controller.Target) {
+			continue
+		}
+
+		c.collectConnected(ch, controller)
+		c.collectRole(ch, controller)
+	}
+
+	return nil
+}
+
+func (c *ControllerCollector) collectConnected(ch chan<- prometheus.Metric, controller ovsmodel.Controller) {
+	connected := float64(0)
+	if controller.IsConnected {
+		connected = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.controllerConnected,
+		prometheus.GaugeValue,
+		connected,
+		controller.Target,
+	)
+}
+
+func (c *ControllerCollector) collectRole(ch chan<- prometheus.Metric, controller ovsmodel.Controller) {
+	ch <- prometheus.MustNewConstMetric(
+		c.controllerRole,
+		prometheus.GaugeValue,
+		mapControllerRole(string(controller.Role)),
+		controller.Target,
+	)
+}
+
+func mapControllerRole(role string) float64 {
+	switch role {
+	case "slave":
+		return 0
+	case "master":
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type DatapathCollector struct {
+	logger log.Logger
+	ovs    client.Client
+
+	datapathCtZones *prometheus.Desc
+}
+
+func NewDatapathCollector(logger log.Logger, ovs client.Client) *DatapathCollector {
+	return &DatapathCollector{
+		logger: logger,
+		ovs:    ovs,
+
+		datapathCtZones: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "datapath", "ct_zones"),
+			"Number of conntrack zones configured on the datapath",
+			[]string{"name"},
+			nil,
+		),
+	}
+}
+
+func (c *DatapathCollector) Name() string {
+	return "datapath"
+}
+
+func (c *DatapathCollector) Scrape(ch chan<- prometheus.Metric) error {
+	datapaths := &[]ovsmodel.Datapath{}
+	err := c.ovs.List(context.TODO(), datapaths)
+	if err != nil {
+		return fmt.Errorf("listing datapaths: %w", err)
+	}
+
+	for _, datapath := range *datapaths {
+		ch <- prometheus.MustNewConstMetric(
+			c.datapathCtZones,
+			prometheus.GaugeValue,
+			float64(len(datapath.CTZones)),
+			datapath.Name,
+		)
+	}
+
+	return nil
+}
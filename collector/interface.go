@@ -5,6 +5,7 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/go-kit/log"
@@ -17,6 +18,7 @@ import (
 type InterfaceCollector struct {
 	logger log.Logger
 	ovs    client.Client
+	cfg    CollectorConfig
 
 	ifaceAdminState                *prometheus.Desc
 	ifaceBfdState                  *prometheus.Desc
@@ -27,10 +29,11 @@ type InterfaceCollector struct {
 	ifaceStatusTunnelEgressCarrier *prometheus.Desc
 }
 
-func NewInterfaceCollector(logger log.Logger, ovs client.Client) prometheus.Collector {
+func NewInterfaceCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *InterfaceCollector {
 	return &InterfaceCollector{
 		logger: logger,
 		ovs:    ovs,
+		cfg:    cfg,
 
 		ifaceAdminState: prometheus.NewDesc(
 			prometheus.BuildFQName("ovs", "interface", "admin_state"),
@@ -77,25 +80,26 @@ func NewInterfaceCollector(logger log.Logger, ovs client.Client) prometheus.Coll
 	}
 }
 
-func (c *InterfaceCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.ifaceAdminState
-	ch <- c.ifaceBfdState
-	ch <- c.ifaceBfdForwarding
-	ch <- c.ifaceBfdRemoteState
-	ch <- c.ifaceBfdFlapCount
-	ch <- c.ifaceStatistics
-	ch <- c.ifaceStatusTunnelEgressCarrier
+// Name identifies this collector in the ovs_scrape_collector_* self-metrics.
+func (c *InterfaceCollector) Name() string {
+	return "interface"
 }
 
-func (c *InterfaceCollector) Collect(ch chan<- prometheus.Metric) {
+// Scrape lists the Interface table and emits metrics for each row allowed
+// by c.cfg, returning an error if the OVSDB lookup itself fails so the
+// caller can report it via ovs_scrape_collector_success.
+func (c *InterfaceCollector) Scrape(ch chan<- prometheus.Metric) error {
 	interfaces := &[]ovsmodel.Interface{}
 	err := c.ovs.List(context.TODO(), interfaces)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Error listing interfaces", "err", err)
-		return
+		return fmt.Errorf("listing interfaces: %w", err)
 	}
 
 	for _, iface := range *interfaces {
+		if !c.cfg.interfaceAllowed(iface.Name, iface.Type) {
+			continue
+		}
+
 		c.collectAdminState(ch, iface)
 		c.collectBfdState(ch, iface)
 		c.collectBfdForwarding(ch, iface)
@@ -104,6 +108,8 @@ func (c *InterfaceCollector) Collect(ch chan<- prometheus.Metric) {
 		c.collectStatistics(ch, iface)
 		c.collectStatusTunnelEgressCarrier(ch, iface)
 	}
+
+	return nil
 }
 
 func (c *InterfaceCollector) collectAdminState(ch chan<- prometheus.Metric, iface ovsmodel.Interface) {
@@ -0,0 +1,69 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type ManagerCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	managerConnected *prometheus.Desc
+}
+
+func NewManagerCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *ManagerCollector {
+	return &ManagerCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		managerConnected: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "manager", "connected"),
+			"Whether the switch has an active connection to the manager",
+			[]string{"target"},
+			nil,
+		),
+	}
+}
+
+func (c *ManagerCollector) Name() string {
+	return "manager"
+}
+
+func (c *ManagerCollector) Scrape(ch chan<- prometheus.Metric) error {
+	managers := &[]ovsmodel.Manager{}
+	err := c.ovs.List(context.TODO(), managers)
+	if err != nil {
+		return fmt.Errorf("listing managers: %w", err)
+	}
+
+	for _, manager := range *managers {
+		if !c.cfg.managerAllowed(manager.Target) {
+			continue
+		}
+
+		connected := float64(0)
+		if manager.IsConnected {
+			connected = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.managerConnected,
+			prometheus.GaugeValue,
+			connected,
+			manager.Target,
+		)
+	}
+
+	return nil
+}
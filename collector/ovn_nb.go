@@ -0,0 +1,139 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovnmodel"
+)
+
+// OVNNBCollector exposes logical switch, logical router, and ACL counts
+// from the OVN Northbound database.
+type OVNNBCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	logicalSwitchCount     *prometheus.Desc
+	logicalRouterCount     *prometheus.Desc
+	logicalSwitchPortCount *prometheus.Desc
+	logicalSwitchACLCount  *prometheus.Desc
+	logicalRouterPortCount *prometheus.Desc
+}
+
+func NewOVNNBCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *OVNNBCollector {
+	return &OVNNBCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		logicalSwitchCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "nb", "logical_switch_count"),
+			"Number of logical switches in the Northbound database that pass the logical switch name filter",
+			nil,
+			nil,
+		),
+		logicalRouterCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "nb", "logical_router_count"),
+			"Number of logical routers in the Northbound database that pass the logical router name filter",
+			nil,
+			nil,
+		),
+		logicalSwitchPortCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "nb", "logical_switch_port_count"),
+			"Number of ports attached to the logical switch",
+			[]string{"logical_switch"},
+			nil,
+		),
+		logicalSwitchACLCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "nb", "logical_switch_acl_count"),
+			"Number of ACLs attached to the logical switch",
+			[]string{"logical_switch"},
+			nil,
+		),
+		logicalRouterPortCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "nb", "logical_router_port_count"),
+			"Number of ports attached to the logical router",
+			[]string{"logical_router"},
+			nil,
+		),
+	}
+}
+
+func (c *OVNNBCollector) Name() string {
+	return "ovn_nb"
+}
+
+func (c *OVNNBCollector) Scrape(ch chan<- prometheus.Metric) error {
+	if err := c.scrapeLogicalSwitches(ch); err != nil {
+		return err
+	}
+
+	if err := c.scrapeLogicalRouters(ch); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *OVNNBCollector) scrapeLogicalSwitches(ch chan<- prometheus.Metric) error {
+	switches := &[]ovnmodel.LogicalSwitch{}
+	if err := c.ovs.List(context.TODO(), switches); err != nil {
+		return fmt.Errorf("listing logical switches: %w", err)
+	}
+
+	count := 0
+	for _, ls := range *switches {
+		if !c.cfg.logicalSwitchAllowed(ls.Name) {
+			continue
+		}
+		count++
+
+		ch <- prometheus.MustNewConstMetric(
+			c.logicalSwitchPortCount,
+			prometheus.GaugeValue,
+			float64(len(ls.Ports)),
+			ls.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.logicalSwitchACLCount,
+			prometheus.GaugeValue,
+			float64(len(ls.ACLs)),
+			ls.Name,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(c.logicalSwitchCount, prometheus.GaugeValue, float64(count))
+
+	return nil
+}
+
+func (c *OVNNBCollector) scrapeLogicalRouters(ch chan<- prometheus.Metric) error {
+	routers := &[]ovnmodel.LogicalRouter{}
+	if err := c.ovs.List(context.TODO(), routers); err != nil {
+		return fmt.Errorf("listing logical routers: %w", err)
+	}
+
+	count := 0
+	for _, lr := range *routers {
+		if !c.cfg.logicalRouterAllowed(lr.Name) {
+			continue
+		}
+		count++
+
+		ch <- prometheus.MustNewConstMetric(
+			c.logicalRouterPortCount,
+			prometheus.GaugeValue,
+			float64(len(lr.Ports)),
+			lr.Name,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(c.logicalRouterCount, prometheus.GaugeValue, float64(count))
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovnmodel"
+)
+
+// OVNSBCollector exposes chassis presence, port-binding counts, and
+// MAC_Binding table size from the OVN Southbound database.
+//
+// Presence of a row in the Chassis table only reflects the chassis that
+// last registered with this Southbound database, not a live heartbeat, so
+// ovn_sb_chassis_up should be read as "known to the database" rather than
+// "reachable right now".
+//
+// Chassis.Encaps and Port_Binding.chassis are references (row UUIDs),
+// so both metrics below resolve them to Encap.Type/Chassis.Name before
+// emitting - otherwise chassis_up's chassis label would be a Chassis.Name
+// while chassis_port_binding_count's would be a different table's UUID,
+// and the two could never be joined on chassis in PromQL.
+type OVNSBCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	chassisUp        *prometheus.Desc
+	portBindingCount *prometheus.Desc
+	macBindingCount  *prometheus.Desc
+}
+
+func NewOVNSBCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *OVNSBCollector {
+	return &OVNSBCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		chassisUp: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "sb", "chassis_up"),
+			"Whether the chassis is present in the Southbound database",
+			[]string{"chassis", "encap_type"},
+			nil,
+		),
+		portBindingCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "sb", "chassis_port_binding_count"),
+			"Number of port bindings claimed by the chassis",
+			[]string{"chassis"},
+			nil,
+		),
+		macBindingCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovn", "sb", "mac_binding_count"),
+			"Number of rows in the MAC_Binding table",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (c *OVNSBCollector) Name() string {
+	return "ovn_sb"
+}
+
+func (c *OVNSBCollector) Scrape(ch chan<- prometheus.Metric) error {
+	chassisRows := &[]ovnmodel.Chassis{}
+	if err := c.ovs.List(context.TODO(), chassisRows); err != nil {
+		return fmt.Errorf("listing chassis: %w", err)
+	}
+
+	encapRows := &[]ovnmodel.Encap{}
+	if err := c.ovs.List(context.TODO(), encapRows); err != nil {
+		return fmt.Errorf("listing encaps: %w", err)
+	}
+	encapTypeByUUID := make(map[string]string, len(*encapRows))
+	for _, encap := range *encapRows {
+		encapTypeByUUID[encap.UUID] = encap.Type
+	}
+
+	// nameByUUID resolves the Chassis references PortBinding.Chassis points
+	// at (Chassis.Encaps is resolved separately, via encapTypeByUUID), so
+	// that chassis_up and chassis_port_binding_count share the same
+	// chassis label value (Chassis.Name) and can be joined in PromQL.
+	nameByUUID := make(map[string]string, len(*chassisRows))
+	for _, chassis := range *chassisRows {
+		nameByUUID[chassis.UUID] = chassis.Name
+	}
+
+	c.scrapeChassis(ch, *chassisRows, encapTypeByUUID)
+
+	if err := c.scrapePortBindings(ch, nameByUUID); err != nil {
+		return err
+	}
+
+	if err := c.scrapeMacBindings(ch); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *OVNSBCollector) scrapeChassis(ch chan<- prometheus.Metric, chassisRows []ovnmodel.Chassis, encapTypeByUUID map[string]string) {
+	for _, chassis := range chassisRows {
+		if !c.cfg.chassisAllowed(chassis.Name) {
+			continue
+		}
+
+		encapType := "unknown"
+		if len(chassis.Encaps) > 0 {
+			if t, ok := encapTypeByUUID[chassis.Encaps[0]]; ok {
+				encapType = t
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.chassisUp, prometheus.GaugeValue, 1, chassis.Name, encapType)
+	}
+}
+
+func (c *OVNSBCollector) scrapePortBindings(ch chan<- prometheus.Metric, nameByUUID map[string]string) error {
+	bindings := &[]ovnmodel.PortBinding{}
+	if err := c.ovs.List(context.TODO(), bindings); err != nil {
+		return fmt.Errorf("listing port bindings: %w", err)
+	}
+
+	countByChassis := make(map[string]int)
+	for _, pb := range *bindings {
+		if pb.Chassis == nil {
+			continue
+		}
+
+		name, ok := nameByUUID[*pb.Chassis]
+		if !ok {
+			continue
+		}
+		countByChassis[name]++
+	}
+
+	for chassis, count := range countByChassis {
+		ch <- prometheus.MustNewConstMetric(c.portBindingCount, prometheus.GaugeValue, float64(count), chassis)
+	}
+
+	return nil
+}
+
+func (c *OVNSBCollector) scrapeMacBindings(ch chan<- prometheus.Metric) error {
+	bindings := &[]ovnmodel.MACBinding{}
+	if err := c.ovs.List(context.TODO(), bindings); err != nil {
+		return fmt.Errorf("listing MAC bindings: %w", err)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.macBindingCount, prometheus.GaugeValue, float64(len(*bindings)))
+
+	return nil
+}
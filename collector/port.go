@@ -0,0 +1,112 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type PortCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	portTag        *prometheus.Desc
+	portTrunks     *prometheus.Desc
+	portBondActive *prometheus.Desc
+}
+
+func NewPortCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *PortCollector {
+	return &PortCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		portTag: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "port", "tag"),
+			"VLAN tag configured on the port, or -1 if untagged",
+			[]string{"name"},
+			nil,
+		),
+		portTrunks: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "port", "trunks"),
+			"Number of VLANs in the port's trunk list",
+			[]string{"name"},
+			nil,
+		),
+		portBondActive: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "port", "bond_active"),
+			"Whether the port has an active bond slave",
+			[]string{"name"},
+			nil,
+		),
+	}
+}
+
+func (c *PortCollector) Name() string {
+	return "port"
+}
+
+func (c *PortCollector) Scrape(ch chan<- prometheus.Metric) error {
+	ports := &[]ovsmodel.Port{}
+	err := c.ovs.List(context.TODO(), ports)
+	if err != nil {
+		return fmt.Errorf("listing ports: %w", err)
+	}
+
+	for _, port := range *ports {
+		if !c.cfg.portAllowed(port.Name) {
+			continue
+		}
+
+		c.collectTag(ch, port)
+		c.collectTrunks(ch, port)
+		c.collectBondActive(ch, port)
+	}
+
+	return nil
+}
+
+func (c *PortCollector) collectTag(ch chan<- prometheus.Metric, port ovsmodel.Port) {
+	tag := float64(-1)
+	if port.Tag != nil {
+		tag = float64(*port.Tag)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.portTag,
+		prometheus.GaugeValue,
+		tag,
+		port.Name,
+	)
+}
+
+func (c *PortCollector) collectTrunks(ch chan<- prometheus.Metric, port ovsmodel.Port) {
+	ch <- prometheus.MustNewConstMetric(
+		c.portTrunks,
+		prometheus.GaugeValue,
+		float64(len(port.Trunks)),
+		port.Name,
+	)
+}
+
+func (c *PortCollector) collectBondActive(ch chan<- prometheus.Metric, port ovsmodel.Port) {
+	bondActive := float64(0)
+	if port.BondActiveSlave != nil && *port.BondActiveSlave != "" {
+		bondActive = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.portBondActive,
+		prometheus.GaugeValue,
+		bondActive,
+		port.Name,
+	)
+}
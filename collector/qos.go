@@ -0,0 +1,65 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+type QoSCollector struct {
+	logger log.Logger
+	ovs    client.Client
+	cfg    CollectorConfig
+
+	qosQueueCount *prometheus.Desc
+}
+
+func NewQoSCollector(logger log.Logger, ovs client.Client, cfg CollectorConfig) *QoSCollector {
+	return &QoSCollector{
+		logger: logger,
+		ovs:    ovs,
+		cfg:    cfg,
+
+		qosQueueCount: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "qos", "queue_count"),
+			"Number of queues configured for the QoS policy",
+			[]string{"uuid", "type"},
+			nil,
+		),
+	}
+}
+
+func (c *QoSCollector) Name() string {
+	return "qos"
+}
+
+func (c *QoSCollector) Scrape(ch chan<- prometheus.Metric) error {
+	qoses := &[]ovsmodel.QoS{}
+	err := c.ovs.List(context.TODO(), qoses)
+	if err != nil {
+		return fmt.Errorf("listing QoS policies: %w", err)
+	}
+
+	for _, qos := range *qoses {
+		if !c.cfg.qosAllowed(qos.Type) {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.qosQueueCount,
+			prometheus.GaugeValue,
+			float64(len(qos.Queues)),
+			qos.UUID,
+			qos.Type,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vexxhost/ovs_exporter/ovsmodel"
+)
+
+// QueueCollector exposes the min-rate/max-rate limits configured on each
+// OVSDB Queue row.
+//
+// These are the only per-queue numbers OVSDB carries: actual queue
+// transmit/drop counters are an OpenFlow OFPMP_QUEUE_STATS concept, which
+// neither this collector nor FlowCollector's ovs-ofctl path currently
+// dumps. The metric names below say "limit", not "rate" or "bytes_total",
+// so they are not mistaken for observed traffic counters.
+type QueueCollector struct {
+	logger log.Logger
+	ovs    client.Client
+
+	queueMinRateLimit *prometheus.Desc
+	queueMaxRateLimit *prometheus.Desc
+}
+
+func NewQueueCollector(logger log.Logger, ovs client.Client) *QueueCollector {
+	return &QueueCollector{
+		logger: logger,
+		ovs:    ovs,
+
+		queueMinRateLimit: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "queue", "min_rate_limit_bytes"),
+			"Configured minimum transmit rate limit for the queue, in bytes per second",
+			[]string{"uuid"},
+			nil,
+		),
+		queueMaxRateLimit: prometheus.NewDesc(
+			prometheus.BuildFQName("ovs", "queue", "max_rate_limit_bytes"),
+			"Configured maximum transmit rate limit for the queue, in bytes per second",
+			[]string{"uuid"},
+			nil,
+		),
+	}
+}
+
+func (c *QueueCollector) Name() string {
+	return "queue"
+}
+
+func (c *QueueCollector) Scrape(ch chan<- prometheus.Metric) error {
+	queues := &[]ovsmodel.Queue{}
+	err := c.ovs.List(context.TODO(), queues)
+	if err != nil {
+		return fmt.Errorf("listing queues: %w", err)
+	}
+
+	for _, queue := range *queues {
+		c.collectRateLimit(ch, c.queueMinRateLimit, queue, "min-rate")
+		c.collectRateLimit(ch, c.queueMaxRateLimit, queue, "max-rate")
+	}
+
+	return nil
+}
+
+func (c *QueueCollector) collectRateLimit(ch chan<- prometheus.Metric, desc *prometheus.Desc, queue ovsmodel.Queue, key string) {
+	raw, ok := queue.OtherConfig[key]
+	if !ok {
+		return
+	}
+
+	rate, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "Error parsing queue rate", "key", key, "err", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, rate, queue.UUID)
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vexxhost/ovs_exporter/collector"
+)
+
+// Config is the schema for --config.file. It defines the probe modules
+// that /probe accepts via its `module` query parameter, so that a single
+// exporter can be pointed at many OVSDB endpoints with different
+// collectors and filters enabled for each.
+type Config struct {
+	Modules map[string]ModuleConfig `yaml:"modules"`
+}
+
+// ModuleConfig describes one OVSDB endpoint and how to scrape it.
+type ModuleConfig struct {
+	Endpoint   string        `yaml:"endpoint"`
+	TLS        *TLSConfig    `yaml:"tls,omitempty"`
+	Collectors []string      `yaml:"collectors,omitempty"`
+	Filters    FiltersConfig `yaml:"filters,omitempty"`
+
+	// OVNNBEndpoint and OVNSBEndpoint, when set, let this module also
+	// scrape an OVN Northbound and/or Southbound database, in addition to
+	// the OVSDB endpoint above. They fall back to --ovn-nb.endpoint and
+	// --ovn-sb.endpoint when unset.
+	OVNNBEndpoint string `yaml:"ovn_nb_endpoint,omitempty"`
+	OVNSBEndpoint string `yaml:"ovn_sb_endpoint,omitempty"`
+
+	// OVNNBTLS and OVNSBTLS carry the TLS material for OVNNBEndpoint and
+	// OVNSBEndpoint, which commonly live on different hosts than the
+	// OVSDB endpoint above and so may need different CAs/certificates.
+	// Each falls back to TLS when unset, and to the --ovsdb.tls.* flags
+	// when that is also unset.
+	OVNNBTLS *TLSConfig `yaml:"ovn_nb_tls,omitempty"`
+	OVNSBTLS *TLSConfig `yaml:"ovn_sb_tls,omitempty"`
+
+	// FlowTarget overrides the ovs-ofctl target the flow collector dials
+	// for each bridge it discovers (a "%s" verb is replaced with the
+	// bridge name). Falls back to --flow.target, then to the bridge's
+	// local mgmt socket. Set this to a `tcp:host:port` OpenFlow listener
+	// to scrape flow stats for a bridge that is not local to the exporter.
+	FlowTarget string `yaml:"flow_target,omitempty"`
+}
+
+// TLSConfig is the material used to dial an `ssl://` or `tcp+tls://` OVSDB
+// endpoint.
+type TLSConfig struct {
+	CAFile     string `yaml:"ca_file,omitempty"`
+	CertFile   string `yaml:"cert_file,omitempty"`
+	KeyFile    string `yaml:"key_file,omitempty"`
+	ServerName string `yaml:"server_name,omitempty"`
+}
+
+// resolveTLS returns cfg if set, otherwise fallback, so that a database
+// endpoint without its own TLS block (e.g. OVNNBTLS) inherits the next
+// one up the chain instead of connecting in the clear.
+func resolveTLS(cfg, fallback *TLSConfig) *TLSConfig {
+	if cfg != nil {
+		return cfg
+	}
+	return fallback
+}
+
+// fingerprint identifies the TLS material t resolves to, so that a
+// clientPool can tell a rotated cert/CA (a changed file path) apart from
+// the connection it already has pooled for an endpoint. It is a fingerprint
+// of configuration, not file contents: replacing a CA bundle in place
+// without changing its path is not detected, and picking that up still
+// requires the pooled connection to be idle long enough to be reaped.
+func (t *TLSConfig) fingerprint() string {
+	if t == nil {
+		return ""
+	}
+	return strings.Join([]string{t.CAFile, t.CertFile, t.KeyFile, t.ServerName}, "\x00")
+}
+
+// build loads t into a *tls.Config suitable for client.WithTLSConfig, or
+// returns (nil, nil) for a nil or entirely empty TLSConfig so that callers
+// can pass the result straight to a plain unix:/tcp: endpoint.
+func (t *TLSConfig) build() (*tls.Config, error) {
+	if t == nil || (t.CAFile == "" && t.CertFile == "" && t.KeyFile == "" && t.ServerName == "") {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: t.ServerName}
+
+	if t.CAFile != "" {
+		ca, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file %q: %w", t.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA file %q", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate from %q and %q: %w", t.CertFile, t.KeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// FiltersConfig holds the include/exclude regexes applied before metrics
+// are emitted for a module. QueueCollector has no natural name to filter
+// on (ovsmodel.Queue is only ever referenced by its row UUID), so it has
+// no entry here and always emits every queue.
+type FiltersConfig struct {
+	InterfaceName     RegexFilter `yaml:"interface_name,omitempty"`
+	InterfaceType     RegexFilter `yaml:"interface_type,omitempty"`
+	BridgeName        RegexFilter `yaml:"bridge_name,omitempty"`
+	PortName          RegexFilter `yaml:"port_name,omitempty"`
+	ControllerTarget  RegexFilter `yaml:"controller_target,omitempty"`
+	ManagerTarget     RegexFilter `yaml:"manager_target,omitempty"`
+	QoSType           RegexFilter `yaml:"qos_type,omitempty"`
+	LogicalSwitchName RegexFilter `yaml:"logical_switch_name,omitempty"`
+	LogicalRouterName RegexFilter `yaml:"logical_router_name,omitempty"`
+	ChassisName       RegexFilter `yaml:"chassis_name,omitempty"`
+}
+
+// RegexFilter keeps a row when it matches Include (or Include is unset)
+// and does not match Exclude.
+type RegexFilter struct {
+	Include string `yaml:"include,omitempty"`
+	Exclude string `yaml:"exclude,omitempty"`
+}
+
+func (f RegexFilter) compile() (include, exclude *regexp.Regexp, err error) {
+	if f.Include != "" {
+		if include, err = regexp.Compile(f.Include); err != nil {
+			return nil, nil, fmt.Errorf("compiling include regex %q: %w", f.Include, err)
+		}
+	}
+	if f.Exclude != "" {
+		if exclude, err = regexp.Compile(f.Exclude); err != nil {
+			return nil, nil, fmt.Errorf("compiling exclude regex %q: %w", f.Exclude, err)
+		}
+	}
+	return include, exclude, nil
+}
+
+// CollectorConfig compiles this module's filters into the form collectors
+// expect.
+func (m ModuleConfig) CollectorConfig() (collector.CollectorConfig, error) {
+	var cfg collector.CollectorConfig
+	var err error
+
+	if cfg.InterfaceNameInclude, cfg.InterfaceNameExclude, err = m.Filters.InterfaceName.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.InterfaceTypeInclude, cfg.InterfaceTypeExclude, err = m.Filters.InterfaceType.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.BridgeNameInclude, cfg.BridgeNameExclude, err = m.Filters.BridgeName.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.PortNameInclude, cfg.PortNameExclude, err = m.Filters.PortName.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.ControllerTargetInclude, cfg.ControllerTargetExclude, err = m.Filters.ControllerTarget.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.ManagerTargetInclude, cfg.ManagerTargetExclude, err = m.Filters.ManagerTarget.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.QoSTypeInclude, cfg.QoSTypeExclude, err = m.Filters.QoSType.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.LogicalSwitchNameInclude, cfg.LogicalSwitchNameExclude, err = m.Filters.LogicalSwitchName.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.LogicalRouterNameInclude, cfg.LogicalRouterNameExclude, err = m.Filters.LogicalRouterName.compile(); err != nil {
+		return cfg, err
+	}
+	if cfg.ChassisNameInclude, cfg.ChassisNameExclude, err = m.Filters.ChassisName.compile(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// hasCollector reports whether collector name is enabled for this module.
+// A module with no collectors listed keeps the exporter's historical
+// behaviour of exposing only interface metrics.
+func (m ModuleConfig) hasCollector(name string) bool {
+	if len(m.Collectors) == 0 {
+		return name == "interface"
+	}
+	for _, c := range m.Collectors {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+
+	return cfg, nil
+}
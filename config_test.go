@@ -0,0 +1,213 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegexFilterCompile(t *testing.T) {
+	t.Run("empty filter compiles to nil matchers", func(t *testing.T) {
+		include, exclude, err := RegexFilter{}.compile()
+		if err != nil {
+			t.Fatalf("compile: %v", err)
+		}
+		if include != nil || exclude != nil {
+			t.Fatalf("compile() = (%v, %v), want (nil, nil)", include, exclude)
+		}
+	})
+
+	t.Run("valid include/exclude compile", func(t *testing.T) {
+		include, exclude, err := RegexFilter{Include: "^eth", Exclude: "0$"}.compile()
+		if err != nil {
+			t.Fatalf("compile: %v", err)
+		}
+		if include == nil || !include.MatchString("eth1") {
+			t.Error("include regex did not compile to a matcher for eth1")
+		}
+		if exclude == nil || !exclude.MatchString("eth0") {
+			t.Error("exclude regex did not compile to a matcher for eth0")
+		}
+	})
+
+	t.Run("invalid include regex errors", func(t *testing.T) {
+		if _, _, err := (RegexFilter{Include: "("}).compile(); err == nil {
+			t.Fatal("compile() with an invalid include regex returned nil error")
+		}
+	})
+
+	t.Run("invalid exclude regex errors", func(t *testing.T) {
+		if _, _, err := (RegexFilter{Exclude: "("}).compile(); err == nil {
+			t.Fatal("compile() with an invalid exclude regex returned nil error")
+		}
+	})
+}
+
+func TestModuleConfigHasCollector(t *testing.T) {
+	tests := []struct {
+		name       string
+		collectors []string
+		query      string
+		want       bool
+	}{
+		{name: "no collectors configured defaults to interface", collectors: nil, query: "interface", want: true},
+		{name: "no collectors configured excludes others", collectors: nil, query: "bridge", want: false},
+		{name: "explicit list includes listed collector", collectors: []string{"bridge", "port"}, query: "port", want: true},
+		{name: "explicit list excludes unlisted collector", collectors: []string{"bridge", "port"}, query: "interface", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ModuleConfig{Collectors: tt.collectors}
+			if got := m.hasCollector(tt.query); got != tt.want {
+				t.Errorf("hasCollector(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTLSConfigBuild(t *testing.T) {
+	t.Run("nil config builds to nil", func(t *testing.T) {
+		var tlsCfg *TLSConfig
+		got, err := tlsCfg.build()
+		if err != nil || got != nil {
+			t.Fatalf("build() = (%v, %v), want (nil, nil)", got, err)
+		}
+	})
+
+	t.Run("entirely empty config builds to nil", func(t *testing.T) {
+		got, err := (&TLSConfig{}).build()
+		if err != nil || got != nil {
+			t.Fatalf("build() = (%v, %v), want (nil, nil)", got, err)
+		}
+	})
+
+	t.Run("server name alone builds a non-nil config", func(t *testing.T) {
+		got, err := (&TLSConfig{ServerName: "ovn-nb.example.com"}).build()
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if got == nil || got.ServerName != "ovn-nb.example.com" {
+			t.Fatalf("build() = %+v, want ServerName set", got)
+		}
+	})
+
+	t.Run("missing CA file errors", func(t *testing.T) {
+		if _, err := (&TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing-ca.pem")}).build(); err == nil {
+			t.Fatal("build() with a missing CA file returned nil error")
+		}
+	})
+}
+
+func TestTLSConfigFingerprint(t *testing.T) {
+	var nilCfg *TLSConfig
+	if got := nilCfg.fingerprint(); got != "" {
+		t.Fatalf("nil fingerprint() = %q, want empty", got)
+	}
+
+	a := &TLSConfig{CAFile: "/etc/ovs/ca.pem", ServerName: "ovsdb"}
+	b := &TLSConfig{CAFile: "/etc/ovs/ca.pem", ServerName: "ovsdb"}
+	c := &TLSConfig{CAFile: "/etc/ovs/rotated-ca.pem", ServerName: "ovsdb"}
+
+	if a.fingerprint() != b.fingerprint() {
+		t.Error("identical TLSConfig values produced different fingerprints")
+	}
+	if a.fingerprint() == c.fingerprint() {
+		t.Error("a rotated CA file path did not change the fingerprint")
+	}
+}
+
+func TestResolveTLS(t *testing.T) {
+	moduleTLS := &TLSConfig{ServerName: "module"}
+	defaultTLS := &TLSConfig{ServerName: "default"}
+
+	if got := resolveTLS(moduleTLS, defaultTLS); got != moduleTLS {
+		t.Errorf("resolveTLS(module, default) = %v, want module", got)
+	}
+	if got := resolveTLS(nil, defaultTLS); got != defaultTLS {
+		t.Errorf("resolveTLS(nil, default) = %v, want default", got)
+	}
+
+	// The OVN NB/SB fallback chain used by probeHandler: a module's
+	// OVNNBTLS/OVNSBTLS falls back to its TLS block, which falls back to
+	// the process-wide default built from --ovsdb.tls.*.
+	ovsTLS := resolveTLS(moduleTLS, defaultTLS)
+	if got := resolveTLS(nil, ovsTLS); got != moduleTLS {
+		t.Errorf("an unset OVN TLS block falling back to the module TLS block = %v, want module", got)
+	}
+
+	nbTLS := &TLSConfig{ServerName: "ovn-nb"}
+	if got := resolveTLS(nbTLS, ovsTLS); got != nbTLS {
+		t.Errorf("an explicit OVN NB TLS block = %v, want nbTLS", got)
+	}
+}
+
+func TestModuleConfigCollectorConfigPropagatesFilters(t *testing.T) {
+	m := ModuleConfig{
+		Filters: FiltersConfig{
+			BridgeName: RegexFilter{Include: "^br-"},
+			ChassisName: RegexFilter{
+				Exclude: "^draining-",
+			},
+		},
+	}
+
+	cfg, err := m.CollectorConfig()
+	if err != nil {
+		t.Fatalf("CollectorConfig: %v", err)
+	}
+	if cfg.BridgeNameInclude == nil || !cfg.BridgeNameInclude.MatchString("br-int") {
+		t.Error("BridgeNameInclude was not compiled from the module's filters")
+	}
+	if cfg.ChassisNameExclude == nil || !cfg.ChassisNameExclude.MatchString("draining-1") {
+		t.Error("ChassisNameExclude was not compiled from the module's filters")
+	}
+}
+
+func TestModuleConfigCollectorConfigRejectsBadRegex(t *testing.T) {
+	m := ModuleConfig{Filters: FiltersConfig{BridgeName: RegexFilter{Include: "("}}}
+
+	if _, err := m.CollectorConfig(); err == nil {
+		t.Fatal("CollectorConfig() with an invalid filter regex returned nil error")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	const yaml = `
+modules:
+  ovn:
+    endpoint: unix:/var/run/openvswitch/db.sock
+    collectors: [interface, ovn_nb]
+    ovn_nb_endpoint: tcp:127.0.0.1:6641
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	m, ok := cfg.Modules["ovn"]
+	if !ok {
+		t.Fatal(`loadConfig did not populate the "ovn" module`)
+	}
+	if m.OVNNBEndpoint != "tcp:127.0.0.1:6641" {
+		t.Errorf("OVNNBEndpoint = %q, want tcp:127.0.0.1:6641", m.OVNNBEndpoint)
+	}
+	if !m.hasCollector("ovn_nb") {
+		t.Error(`hasCollector("ovn_nb") = false, want true`)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("loadConfig() on a missing file returned nil error")
+	}
+}
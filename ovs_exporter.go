@@ -4,13 +4,11 @@
 package main
 
 import (
-	"context"
 	"net/http"
 	"os"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/go-kit/log/level"
-	"github.com/ovn-org/libovsdb/client"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/promlog"
@@ -18,21 +16,60 @@ import (
 	"github.com/prometheus/common/version"
 	"github.com/prometheus/exporter-toolkit/web"
 	webflag "github.com/prometheus/exporter-toolkit/web/kingpinflag"
-	"github.com/tonglil/gokitlogr"
 
-	"github.com/vexxhost/ovs_exporter/collector"
+	"github.com/vexxhost/ovs_exporter/ovnmodel"
 	"github.com/vexxhost/ovs_exporter/ovsmodel"
 )
 
 var (
 	metricsPath = kingpin.Flag(
 		"web.telemetry-path",
-		"Path under which to expose metrics.",
+		"Path under which to expose the exporter's own metrics.",
 	).Default("/metrics").String()
+	probePath = kingpin.Flag(
+		"web.probe-path",
+		"Path under which to expose the probe endpoint.",
+	).Default("/probe").String()
 	ovsdbEndpoint = kingpin.Flag(
 		"ovsdb.endpoint",
-		"Endpoint for OVSDB",
+		"Default endpoint for OVSDB, used by /probe requests that do not specify a target",
 	).Envar("OVSDB_ENDPOINT").Default("unix:/var/run/openvswitch/db.sock").String()
+	ovsdbIdleTimeout = kingpin.Flag(
+		"ovsdb.idle-timeout",
+		"How long to keep a pooled OVSDB connection open after its last probe; 0 disables idle reaping",
+	).Default("5m").Duration()
+	ovnNBEndpoint = kingpin.Flag(
+		"ovn-nb.endpoint",
+		"Default endpoint for the OVN Northbound database, used by /probe requests enabling the ovn_nb collector that do not specify one",
+	).Envar("OVN_NB_ENDPOINT").String()
+	ovnSBEndpoint = kingpin.Flag(
+		"ovn-sb.endpoint",
+		"Default endpoint for the OVN Southbound database, used by /probe requests enabling the ovn_sb collector that do not specify one",
+	).Envar("OVN_SB_ENDPOINT").String()
+	ovsdbTLSCA = kingpin.Flag(
+		"ovsdb.tls.ca",
+		"Path to the CA bundle used to verify the server certificate of ssl:// and tcp+tls:// endpoints",
+	).Envar("OVSDB_TLS_CA").String()
+	ovsdbTLSCert = kingpin.Flag(
+		"ovsdb.tls.cert",
+		"Path to the client certificate presented for mutual TLS",
+	).Envar("OVSDB_TLS_CERT").String()
+	ovsdbTLSKey = kingpin.Flag(
+		"ovsdb.tls.key",
+		"Path to the client private key presented for mutual TLS",
+	).Envar("OVSDB_TLS_KEY").String()
+	ovsdbTLSServerName = kingpin.Flag(
+		"ovsdb.tls.server-name",
+		"Server name used to verify the server certificate, overriding the one implied by the endpoint",
+	).Envar("OVSDB_TLS_SERVER_NAME").String()
+	flowTarget = kingpin.Flag(
+		"flow.target",
+		"Default ovs-ofctl target for the flow collector, with %s replaced by the bridge name; defaults to each bridge's local mgmt socket",
+	).Envar("FLOW_TARGET").String()
+	configFile = kingpin.Flag(
+		"config.file",
+		"Path to the exporter's YAML configuration file defining probe modules",
+	).String()
 	toolkitFlags = webflag.AddFlags(kingpin.CommandLine, ":9272")
 )
 
@@ -45,38 +82,41 @@ func main() {
 	kingpin.Parse()
 
 	logger := promlog.New(promlogConfig)
-	logr := gokitlogr.New(&logger)
 
 	level.Info(logger).Log("msg", "Starting ovs_exporter", "version", version.Info())
 	level.Info(logger).Log("msg", "Build context", "build_context", version.BuildContext())
 
-	dbModelReq, err := ovsmodel.FullDatabaseModel()
-	if err != nil {
-		level.Error(logger).Log("msg", "Error getting OVSDB model", "err", err)
-		os.Exit(1)
+	var cfg *Config
+	if *configFile != "" {
+		c, err := loadConfig(*configFile)
+		if err != nil {
+			level.Error(logger).Log("msg", "Error loading config file", "err", err)
+			os.Exit(1)
+		}
+		cfg = c
 	}
 
-	ovs, err := client.NewOVSDBClient(dbModelReq, client.WithEndpoint(*ovsdbEndpoint), client.WithLogger(&logr))
-	if err != nil {
-		level.Error(logger).Log("msg", "Error creating OVSDB client", "err", err)
-		os.Exit(1)
-	}
+	pool := newClientPool(logger, *ovsdbIdleTimeout, ovsmodel.FullDatabaseModel)
+	defer pool.Close()
 
-	err = ovs.Connect(context.Background())
-	if err != nil {
-		level.Error(logger).Log("msg", "Error connecting to OVSDB", "err", err)
-		os.Exit(1)
-	}
-	defer ovs.Close()
+	nbPool := newClientPool(logger, *ovsdbIdleTimeout, ovnmodel.NBFullDatabaseModel)
+	defer nbPool.Close()
+
+	sbPool := newClientPool(logger, *ovsdbIdleTimeout, ovnmodel.SBFullDatabaseModel)
+	defer sbPool.Close()
 
-	ovs.MonitorAll(context.TODO())
+	defaultTLS := &TLSConfig{
+		CAFile:     *ovsdbTLSCA,
+		CertFile:   *ovsdbTLSCert,
+		KeyFile:    *ovsdbTLSKey,
+		ServerName: *ovsdbTLSServerName,
+	}
 
 	reg := prometheus.NewRegistry()
-	reg.MustRegister(
-		collector.NewInterfaceCollector(logger, ovs),
-	)
+	reg.MustRegister(version.NewCollector("ovs_exporter"))
 
 	http.Handle(*metricsPath, promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	http.HandleFunc(*probePath, probeHandler(logger, pool, nbPool, sbPool, defaultTLS, cfg))
 	if *metricsPath != "/" && *metricsPath != "" {
 		landingConfig := web.LandingConfig{
 			Name:        "Open vSwitch Exporter",
@@ -87,6 +127,10 @@ func main() {
 					Address: *metricsPath,
 					Text:    "Metrics",
 				},
+				{
+					Address: *probePath + "?target=unix:/var/run/openvswitch/db.sock",
+					Text:    "Probe",
+				},
 			},
 		}
 		landingPage, err := web.NewLandingPage(landingConfig)
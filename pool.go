@@ -0,0 +1,203 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/tonglil/gokitlogr"
+)
+
+// dbModelFunc builds the ClientDBModel for the database a clientPool
+// connects to, e.g. ovsmodel.FullDatabaseModel or one of the ovnmodel
+// equivalents. Each database a pool can reach needs its own clientPool
+// since a libovsdb client is bound to a single schema.
+type dbModelFunc func() (model.ClientDBModel, error)
+
+// dialFunc opens a new OVSDB client connection to endpoint. It is a field on
+// clientPool, rather than a call straight to (*clientPool).connect, so that
+// tests can substitute a fake dial and exercise the pooling/singleflight/reap
+// logic without a real OVSDB server.
+type dialFunc func(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error)
+
+// pooledClient is an OVSDB client kept alive between scrapes so that a
+// `/probe` request does not have to pay the cost of `MonitorAll` every time
+// Prometheus polls a target.
+type pooledClient struct {
+	client   client.Client
+	lastUsed time.Time
+}
+
+// inflightConnect lets concurrent Get calls for the same not-yet-pooled
+// endpoint wait on a single in-progress connect instead of each dialing
+// their own client, only one of which would ever make it into p.clients.
+type inflightConnect struct {
+	done   chan struct{}
+	client client.Client
+	err    error
+}
+
+// clientPool hands out OVSDB clients keyed by endpoint, reusing an existing
+// connection when one is already open and reaping connections that have
+// been idle for longer than idleTimeout. All endpoints in a pool share the
+// same dbModel, so separate pools are used for OVSDB, OVN Northbound, and
+// OVN Southbound.
+type clientPool struct {
+	logger      log.Logger
+	idleTimeout time.Duration
+	dbModel     dbModelFunc
+	dial        dialFunc
+
+	mu       sync.Mutex
+	clients  map[string]*pooledClient
+	inflight map[string]*inflightConnect
+
+	stop chan struct{}
+}
+
+func newClientPool(logger log.Logger, idleTimeout time.Duration, dbModel dbModelFunc) *clientPool {
+	p := &clientPool{
+		logger:      logger,
+		idleTimeout: idleTimeout,
+		dbModel:     dbModel,
+		clients:     make(map[string]*pooledClient),
+		inflight:    make(map[string]*inflightConnect),
+		stop:        make(chan struct{}),
+	}
+	p.dial = p.connect
+
+	go p.reap()
+
+	return p
+}
+
+// Get returns an OVSDB client connected to endpoint, reusing a pooled
+// connection when available. The pool is keyed by endpoint plus
+// tlsFingerprint (see TLSConfig.fingerprint), so a config reload that
+// points an endpoint at different TLS material opens a fresh connection
+// under a new key instead of silently reusing one dialed with the old
+// cert/CA; the old entry is left for the reaper to close once idle.
+// Concurrent calls for the same not-yet-pooled key share a single connect
+// attempt rather than racing to open (and leak) one each.
+func (p *clientPool) Get(ctx context.Context, endpoint string, tlsConfig *tls.Config, tlsFingerprint string) (client.Client, error) {
+	key := endpoint
+	if tlsFingerprint != "" {
+		key = endpoint + "\x00" + tlsFingerprint
+	}
+
+	p.mu.Lock()
+	if pc, ok := p.clients[key]; ok {
+		if pc.client.Connected() {
+			pc.lastUsed = time.Now()
+			p.mu.Unlock()
+			return pc.client, nil
+		}
+		pc.client.Close()
+		delete(p.clients, key)
+	}
+
+	if inflight, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		<-inflight.done
+		return inflight.client, inflight.err
+	}
+
+	inflight := &inflightConnect{done: make(chan struct{})}
+	p.inflight[key] = inflight
+	p.mu.Unlock()
+
+	ovs, err := p.dial(ctx, endpoint, tlsConfig)
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	if err == nil {
+		p.clients[key] = &pooledClient{client: ovs, lastUsed: time.Now()}
+	}
+	p.mu.Unlock()
+
+	inflight.client, inflight.err = ovs, err
+	close(inflight.done)
+
+	return ovs, err
+}
+
+func (p *clientPool) connect(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error) {
+	dbModelReq, err := p.dbModel()
+	if err != nil {
+		return nil, fmt.Errorf("building OVSDB model: %w", err)
+	}
+
+	logr := gokitlogr.New(&p.logger)
+	opts := []client.Option{client.WithEndpoint(endpoint), client.WithLogger(&logr)}
+	if tlsConfig != nil {
+		opts = append(opts, client.WithTLSConfig(tlsConfig))
+	}
+
+	ovs, err := client.NewOVSDBClient(dbModelReq, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OVSDB client for %q: %w", endpoint, err)
+	}
+
+	if err := ovs.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connecting to OVSDB at %q: %w", endpoint, err)
+	}
+
+	ovs.MonitorAll(ctx)
+
+	return ovs, nil
+}
+
+// reap closes and evicts pooled clients that have been idle for longer than
+// idleTimeout, so that a long-running exporter does not accumulate one
+// connection per target it has ever been asked to probe. A non-positive
+// idleTimeout disables reaping instead of passing it to time.NewTicker,
+// which panics on values <= 0.
+func (p *clientPool) reap() {
+	if p.idleTimeout <= 0 {
+		<-p.stop
+		return
+	}
+
+	ticker := time.NewTicker(p.idleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			for key, pc := range p.clients {
+				if time.Since(pc.lastUsed) < p.idleTimeout {
+					continue
+				}
+
+				level.Debug(p.logger).Log("msg", "Closing idle OVSDB connection", "key", key)
+				pc.client.Close()
+				delete(p.clients, key)
+			}
+			p.mu.Unlock()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Close stops the reaper and closes every pooled connection.
+func (p *clientPool) Close() {
+	close(p.stop)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, pc := range p.clients {
+		pc.client.Close()
+		delete(p.clients, key)
+	}
+}
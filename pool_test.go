@@ -0,0 +1,171 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/ovn-org/libovsdb/client"
+)
+
+// fakeClient implements client.Client by embedding the (nil) interface and
+// overriding only the methods clientPool actually calls. Any other method
+// would panic on a nil embedded interface, which is fine: the pool never
+// calls them.
+type fakeClient struct {
+	client.Client
+
+	mu        sync.Mutex
+	connected bool
+	closed    bool
+}
+
+func (f *fakeClient) Connected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connected
+}
+
+func (f *fakeClient) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	f.connected = false
+	return nil
+}
+
+func newTestPool(t *testing.T, idleTimeout time.Duration) *clientPool {
+	t.Helper()
+	p := newClientPool(log.NewNopLogger(), idleTimeout, nil)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func TestClientPoolGetSingleflightsConcurrentConnects(t *testing.T) {
+	p := newTestPool(t, time.Minute)
+
+	var dials int32
+	start := make(chan struct{})
+	p.dial = func(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error) {
+		atomic.AddInt32(&dials, 1)
+		<-start // hold every caller here until they've all arrived, to force overlap
+		return &fakeClient{connected: true}, nil
+	}
+
+	const callers = 8
+	results := make([]client.Client, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = p.Get(context.Background(), "tcp:127.0.0.1:6640", nil, "")
+		}(i)
+	}
+
+	// Give every goroutine a chance to either dial or start waiting on the
+	// in-flight connect before releasing the dial.
+	time.Sleep(50 * time.Millisecond)
+	close(start)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Fatalf("dial called %d times, want 1", got)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d: Get returned error: %v", i, err)
+		}
+	}
+	for i := 1; i < callers; i++ {
+		if results[i] != results[0] {
+			t.Fatalf("caller %d got a different client than caller 0", i)
+		}
+	}
+}
+
+func TestClientPoolReapEvictsIdleConnections(t *testing.T) {
+	p := newTestPool(t, 20*time.Millisecond)
+
+	fc := &fakeClient{connected: true}
+	p.dial = func(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error) {
+		return fc, nil
+	}
+
+	if _, err := p.Get(context.Background(), "unix:/var/run/openvswitch/db.sock", nil, ""); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		fc.mu.Lock()
+		closed := fc.closed
+		fc.mu.Unlock()
+		if closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle connection was not reaped in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	p.mu.Lock()
+	_, stillPooled := p.clients["unix:/var/run/openvswitch/db.sock"]
+	p.mu.Unlock()
+	if stillPooled {
+		t.Fatal("reaped connection is still present in p.clients")
+	}
+}
+
+func TestClientPoolZeroIdleTimeoutDisablesReapingWithoutPanicking(t *testing.T) {
+	p := newTestPool(t, 0)
+
+	fc := &fakeClient{connected: true}
+	p.dial = func(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error) {
+		return fc, nil
+	}
+
+	if _, err := p.Get(context.Background(), "unix:/var/run/openvswitch/db.sock", nil, ""); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// newClientPool(..., 0, ...) must not have panicked constructing a
+	// time.NewTicker(0) in its reap goroutine, and a non-positive
+	// idleTimeout must never evict a connection.
+	time.Sleep(50 * time.Millisecond)
+
+	fc.mu.Lock()
+	closed := fc.closed
+	fc.mu.Unlock()
+	if closed {
+		t.Fatal("connection was reaped despite idleTimeout <= 0")
+	}
+}
+
+func TestClientPoolGetKeysOnTLSFingerprint(t *testing.T) {
+	p := newTestPool(t, time.Minute)
+
+	var dials int32
+	p.dial = func(ctx context.Context, endpoint string, tlsConfig *tls.Config) (client.Client, error) {
+		atomic.AddInt32(&dials, 1)
+		return &fakeClient{connected: true}, nil
+	}
+
+	_, _ = p.Get(context.Background(), "tcp:127.0.0.1:6640", nil, "fingerprint-a")
+	_, _ = p.Get(context.Background(), "tcp:127.0.0.1:6640", nil, "fingerprint-a")
+	_, _ = p.Get(context.Background(), "tcp:127.0.0.1:6640", nil, "fingerprint-b")
+
+	if got := atomic.LoadInt32(&dials); got != 2 {
+		t.Fatalf("dial called %d times, want 2 (one per distinct fingerprint)", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 VEXXHOST, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/vexxhost/ovs_exporter/collector"
+)
+
+// probeHandler implements the Prometheus multi-target exporter pattern:
+// each request probes the OVSDB endpoint given by the `target` query
+// parameter (or the module's configured endpoint) and returns a scrape
+// containing only that target's metrics, built from a fresh registry so
+// one target's labels never bleed into another's.
+func probeHandler(logger log.Logger, pool, nbPool, sbPool *clientPool, defaultTLS *TLSConfig, cfg *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+
+		moduleName := params.Get("module")
+		if moduleName == "" {
+			moduleName = "interface"
+		}
+
+		module := ModuleConfig{}
+		if cfg != nil {
+			m, ok := cfg.Modules[moduleName]
+			if !ok {
+				http.Error(w, "unknown module "+moduleName, http.StatusBadRequest)
+				return
+			}
+			module = m
+		} else if moduleName != "interface" {
+			// With no --config.file loaded there are no modules to look
+			// up, only the zero-value default (which behaves as the
+			// built-in "interface" module). Any other module name must
+			// be rejected here too, or it would silently fall back to
+			// that default instead of reporting the missing config.
+			http.Error(w, "unknown module "+moduleName, http.StatusBadRequest)
+			return
+		}
+
+		target := params.Get("target")
+		if target == "" {
+			target = module.Endpoint
+		}
+		if target == "" {
+			target = *ovsdbEndpoint
+		}
+
+		collectorConfig, err := module.CollectorConfig()
+		if err != nil {
+			level.Error(logger).Log("msg", "Error compiling module filters", "module", moduleName, "err", err)
+			http.Error(w, "Error compiling module filters: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ovsTLS := resolveTLS(module.TLS, defaultTLS)
+		tlsConfig, err := ovsTLS.build()
+		if err != nil {
+			level.Error(logger).Log("msg", "Error building TLS config", "module", moduleName, "err", err)
+			http.Error(w, "Error building TLS config: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ovs, err := pool.Get(r.Context(), target, tlsConfig, ovsTLS.fingerprint())
+		if err != nil {
+			level.Error(logger).Log("msg", "Error connecting to OVSDB target", "target", target, "err", err)
+			http.Error(w, "Error connecting to OVSDB target: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var scrapers []collector.Scraper
+		if module.hasCollector("interface") {
+			scrapers = append(scrapers, collector.NewInterfaceCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("bridge") {
+			scrapers = append(scrapers, collector.NewBridgeCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("port") {
+			scrapers = append(scrapers, collector.NewPortCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("datapath") {
+			scrapers = append(scrapers, collector.NewDatapathCollector(logger, ovs))
+		}
+		if module.hasCollector("controller") {
+			scrapers = append(scrapers, collector.NewControllerCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("manager") {
+			scrapers = append(scrapers, collector.NewManagerCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("qos") {
+			scrapers = append(scrapers, collector.NewQoSCollector(logger, ovs, collectorConfig))
+		}
+		if module.hasCollector("queue") {
+			scrapers = append(scrapers, collector.NewQueueCollector(logger, ovs))
+		}
+		if module.hasCollector("flow") {
+			target := module.FlowTarget
+			if target == "" {
+				target = *flowTarget
+			}
+			scrapers = append(scrapers, collector.NewFlowCollector(logger, ovs, collectorConfig, target))
+		}
+
+		if module.hasCollector("ovn_nb") {
+			nbTarget := module.OVNNBEndpoint
+			if nbTarget == "" {
+				nbTarget = *ovnNBEndpoint
+			}
+
+			nbTLS := resolveTLS(module.OVNNBTLS, ovsTLS)
+			nbTLSConfig, err := nbTLS.build()
+			if err != nil {
+				level.Error(logger).Log("msg", "Error building OVN Northbound TLS config", "module", moduleName, "err", err)
+				http.Error(w, "Error building OVN Northbound TLS config: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			nb, err := nbPool.Get(r.Context(), nbTarget, nbTLSConfig, nbTLS.fingerprint())
+			if err != nil {
+				level.Error(logger).Log("msg", "Error connecting to OVN Northbound target", "target", nbTarget, "err", err)
+				http.Error(w, "Error connecting to OVN Northbound target: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			scrapers = append(scrapers, collector.NewOVNNBCollector(logger, nb, collectorConfig))
+		}
+
+		if module.hasCollector("ovn_sb") {
+			sbTarget := module.OVNSBEndpoint
+			if sbTarget == "" {
+				sbTarget = *ovnSBEndpoint
+			}
+
+			sbTLS := resolveTLS(module.OVNSBTLS, ovsTLS)
+			sbTLSConfig, err := sbTLS.build()
+			if err != nil {
+				level.Error(logger).Log("msg", "Error building OVN Southbound TLS config", "module", moduleName, "err", err)
+				http.Error(w, "Error building OVN Southbound TLS config: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			sb, err := sbPool.Get(r.Context(), sbTarget, sbTLSConfig, sbTLS.fingerprint())
+			if err != nil {
+				level.Error(logger).Log("msg", "Error connecting to OVN Southbound target", "target", sbTarget, "err", err)
+				http.Error(w, "Error connecting to OVN Southbound target: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			scrapers = append(scrapers, collector.NewOVNSBCollector(logger, sb, collectorConfig))
+		}
+
+		reg := prometheus.NewRegistry()
+		reg.MustRegister(collector.NewExporter(logger, scrapers...))
+
+		promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}
+}